@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Binder validates that the @pick directives used throughout a document's
+// operations are consistent with schema before any template is executed
+// against them. Without this, a mismatched @pick silently drops fields
+// instead of failing loudly (see templates.pickedFieldsForVariableDefinition).
+type Binder struct {
+	schema *ast.Schema
+}
+
+// NewBinder returns a Binder that validates operations against schema.
+func NewBinder(schema *ast.Schema) *Binder {
+	return &Binder{schema: schema}
+}
+
+// ValidateOperation checks every @pick directive attached to operation's
+// variables against schema, and fails if flattening a variable's type would
+// recurse into a cycle.
+func (b *Binder) ValidateOperation(operation *ast.OperationDefinition) error {
+	for _, variableDefinition := range operation.VariableDefinitions {
+		if err := b.validateVariableDefinition(operation, variableDefinition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Binder) validateVariableDefinition(operation *ast.OperationDefinition, variableDefinition *ast.VariableDefinition) error {
+	typeName := innermostTypeName(variableDefinition.Type)
+	typeInfo := b.schema.Types[typeName]
+	if typeInfo == nil {
+		return fmt.Errorf("operation %q: variable %q: unknown type %q", operation.Name, variableDefinition.Variable, typeName)
+	}
+
+	if err := b.checkCycle(typeInfo, nil); err != nil {
+		return fmt.Errorf("operation %q: variable %q: %w", operation.Name, variableDefinition.Variable, err)
+	}
+
+	directive := pickDirective(variableDefinition.Directives)
+	if directive == nil {
+		return nil
+	}
+
+	if !isFlattenable(typeInfo) {
+		return fmt.Errorf("operation %q: variable %q: @pick used on %q, which has no fields to pick from", operation.Name, variableDefinition.Variable, typeName)
+	}
+
+	pickedFieldNames, err := pickedFieldNamesFromDirective(directive)
+	if err != nil {
+		return fmt.Errorf("operation %q: variable %q: %w", operation.Name, variableDefinition.Variable, err)
+	}
+
+	fieldsByName := make(map[string]*ast.FieldDefinition, len(typeInfo.Fields))
+	for _, field := range typeInfo.Fields {
+		fieldsByName[field.Name] = field
+	}
+
+	for _, pickedFieldName := range pickedFieldNames {
+		field, found := fieldsByName[pickedFieldName]
+		if !found {
+			return fmt.Errorf("operation %q: variable %q: @pick references unknown field %q on type %q", operation.Name, variableDefinition.Variable, pickedFieldName, typeName)
+		}
+
+		fieldTypeInfo := b.schema.Types[innermostTypeName(field.Type)]
+		if fieldTypeInfo != nil && isFlattenable(fieldTypeInfo) {
+			return fmt.Errorf(
+				"operation %q: variable %q: @pick references field %q on type %q, which is %s with no scalar value of its own; @pick can only reference fields with scalar values",
+				operation.Name, variableDefinition.Variable, pickedFieldName, typeName, describeWrapping(field.Type))
+		}
+	}
+
+	return nil
+}
+
+// checkCycle walks typeInfo's object/input-object field graph, failing if
+// it ever revisits a type already on the current path -- the same graph
+// flattenedVariablesForType would otherwise recurse into forever.
+func (b *Binder) checkCycle(typeInfo *ast.Definition, visiting map[string]bool) error {
+	if !isFlattenable(typeInfo) {
+		return nil
+	}
+
+	if visiting == nil {
+		visiting = make(map[string]bool)
+	}
+	if visiting[typeInfo.Name] {
+		return fmt.Errorf("@pick-flattening would cycle back to type %q", typeInfo.Name)
+	}
+	visiting[typeInfo.Name] = true
+	defer delete(visiting, typeInfo.Name)
+
+	for _, field := range typeInfo.Fields {
+		fieldTypeInfo := b.schema.Types[innermostTypeName(field.Type)]
+		if fieldTypeInfo == nil {
+			continue
+		}
+		if err := b.checkCycle(fieldTypeInfo, visiting); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isFlattenable reports whether typeInfo is a type that
+// flattenedVariablesForType would recurse into rather than treat as a leaf.
+func isFlattenable(typeInfo *ast.Definition) bool {
+	return typeInfo.Kind == ast.Object || typeInfo.Kind == ast.InputObject
+}
+
+func describeWrapping(typeNode *ast.Type) string {
+	if typeNode.Elem != nil {
+		return "a list of objects"
+	}
+	return "an object"
+}
+
+func innermostTypeName(typeNode *ast.Type) string {
+	for typeNode.Elem != nil {
+		typeNode = typeNode.Elem
+	}
+	return typeNode.NamedType
+}
+
+func pickDirective(directives ast.DirectiveList) *ast.Directive {
+	for _, directive := range directives {
+		if directive.Name == "pick" {
+			return directive
+		}
+	}
+	return nil
+}
+
+func pickedFieldNamesFromDirective(directive *ast.Directive) ([]string, error) {
+	for _, argument := range directive.Arguments {
+		if argument.Name != "fields" {
+			continue
+		}
+
+		fieldNames := make([]string, 0, len(argument.Value.Children))
+		for _, child := range argument.Value.Children {
+			fieldNames = append(fieldNames, child.Value.Raw)
+		}
+		return fieldNames, nil
+	}
+
+	return nil, fmt.Errorf(`@pick is missing its required "fields" argument`)
+}
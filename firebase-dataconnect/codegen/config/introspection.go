@@ -0,0 +1,227 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// introspectionResult is the subset of a standard GraphQL introspection
+// query response (the `{ __schema { ... } }` query) needed to reconstruct
+// an SDL document.
+type introspectionResult struct {
+	Data struct {
+		Schema introspectionSchema `json:"__schema"`
+	} `json:"data"`
+}
+
+type introspectionSchema struct {
+	QueryType        *introspectionTypeRef    `json:"queryType"`
+	MutationType     *introspectionTypeRef    `json:"mutationType"`
+	SubscriptionType *introspectionTypeRef    `json:"subscriptionType"`
+	Types            []introspectionType      `json:"types"`
+	Directives       []introspectionDirective `json:"directives"`
+}
+
+type introspectionDirective struct {
+	Name      string                    `json:"name"`
+	Locations []string                  `json:"locations"`
+	Args      []introspectionInputValue `json:"args"`
+}
+
+type introspectionTypeRef struct {
+	Name string `json:"name"`
+}
+
+type introspectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Fields        []introspectionField      `json:"fields"`
+	InputFields   []introspectionInputValue `json:"inputFields"`
+	EnumValues    []introspectionEnumValue  `json:"enumValues"`
+	PossibleTypes []introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name string                    `json:"name"`
+	Args []introspectionInputValue `json:"args"`
+	Type introspectionTypeRefFull  `json:"type"`
+}
+
+type introspectionInputValue struct {
+	Name string                   `json:"name"`
+	Type introspectionTypeRefFull `json:"type"`
+}
+
+type introspectionEnumValue struct {
+	Name string `json:"name"`
+}
+
+type introspectionTypeRefFull struct {
+	Kind   string                    `json:"kind"`
+	Name   string                    `json:"name"`
+	OfType *introspectionTypeRefFull `json:"ofType"`
+}
+
+// builtinIntrospectionTypeNames are the introspection metadata and scalar
+// types every schema carries that are already known to gqlparser and must
+// not be redeclared in the reconstructed SDL.
+var builtinIntrospectionTypeNames = map[string]bool{
+	"__Schema": true, "__Type": true, "__Field": true, "__InputValue": true,
+	"__EnumValue": true, "__TypeKind": true, "__Directive": true, "__DirectiveLocation": true,
+	"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true,
+}
+
+// builtinIntrospectionDirectiveNames are the directives gqlparser already
+// declares internally and must not be redeclared in the reconstructed SDL.
+var builtinIntrospectionDirectiveNames = map[string]bool{
+	"skip": true, "include": true, "deprecated": true, "specifiedBy": true,
+}
+
+func loadSchemaFromIntrospection(schemaFile string) (*ast.Schema, error) {
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading introspection dump %q: %w", schemaFile, err)
+	}
+
+	var result introspectionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parsing introspection dump %q: %w", schemaFile, err)
+	}
+
+	sdl := introspectionToSDL(result.Data.Schema)
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: schemaFile, Input: sdl})
+	if err != nil {
+		return nil, fmt.Errorf("parsing schema reconstructed from introspection dump %q: %w", schemaFile, err)
+	}
+
+	return schema, nil
+}
+
+// introspectionToSDL renders an introspection schema dump back into SDL
+// text so it can be parsed by the same gqlparser.LoadSchema path used for
+// hand-written .graphql files.
+func introspectionToSDL(schema introspectionSchema) string {
+	var sb strings.Builder
+
+	if needsExplicitSchemaDefinition(schema) {
+		sb.WriteString("schema {\n")
+		if schema.QueryType != nil {
+			fmt.Fprintf(&sb, "  query: %s\n", schema.QueryType.Name)
+		}
+		if schema.MutationType != nil {
+			fmt.Fprintf(&sb, "  mutation: %s\n", schema.MutationType.Name)
+		}
+		if schema.SubscriptionType != nil {
+			fmt.Fprintf(&sb, "  subscription: %s\n", schema.SubscriptionType.Name)
+		}
+		sb.WriteString("}\n\n")
+	}
+
+	for _, introspectedType := range schema.Types {
+		if builtinIntrospectionTypeNames[introspectedType.Name] {
+			continue
+		}
+		writeTypeSDL(&sb, introspectedType)
+	}
+
+	for _, directive := range schema.Directives {
+		if builtinIntrospectionDirectiveNames[directive.Name] {
+			continue
+		}
+		writeDirectiveSDL(&sb, directive)
+	}
+
+	return sb.String()
+}
+
+// writeDirectiveSDL renders a directive declaration, e.g.
+// `directive @pick(fields: [String!]!) on VARIABLE_DEFINITION`, so directives
+// like @pick that a schema's operations rely on survive the round trip
+// through introspection JSON instead of coming out "unknown directive".
+func writeDirectiveSDL(sb *strings.Builder, directive introspectionDirective) {
+	fmt.Fprintf(sb, "directive @%s%s on %s\n\n", directive.Name, argsSDL(directive.Args), strings.Join(directive.Locations, " | "))
+}
+
+func needsExplicitSchemaDefinition(schema introspectionSchema) bool {
+	if schema.QueryType != nil && schema.QueryType.Name != "Query" {
+		return true
+	}
+	if schema.MutationType != nil && schema.MutationType.Name != "Mutation" {
+		return true
+	}
+	if schema.SubscriptionType != nil && schema.SubscriptionType.Name != "Subscription" {
+		return true
+	}
+	return false
+}
+
+func writeTypeSDL(sb *strings.Builder, introspectedType introspectionType) {
+	switch introspectedType.Kind {
+	case "SCALAR":
+		fmt.Fprintf(sb, "scalar %s\n\n", introspectedType.Name)
+
+	case "OBJECT":
+		fmt.Fprintf(sb, "type %s {\n", introspectedType.Name)
+		for _, field := range introspectedType.Fields {
+			fmt.Fprintf(sb, "  %s%s: %s\n", field.Name, argsSDL(field.Args), typeRefToSDL(field.Type))
+		}
+		sb.WriteString("}\n\n")
+
+	case "INTERFACE":
+		fmt.Fprintf(sb, "interface %s {\n", introspectedType.Name)
+		for _, field := range introspectedType.Fields {
+			fmt.Fprintf(sb, "  %s%s: %s\n", field.Name, argsSDL(field.Args), typeRefToSDL(field.Type))
+		}
+		sb.WriteString("}\n\n")
+
+	case "INPUT_OBJECT":
+		fmt.Fprintf(sb, "input %s {\n", introspectedType.Name)
+		for _, inputField := range introspectedType.InputFields {
+			fmt.Fprintf(sb, "  %s: %s\n", inputField.Name, typeRefToSDL(inputField.Type))
+		}
+		sb.WriteString("}\n\n")
+
+	case "ENUM":
+		fmt.Fprintf(sb, "enum %s {\n", introspectedType.Name)
+		for _, enumValue := range introspectedType.EnumValues {
+			fmt.Fprintf(sb, "  %s\n", enumValue.Name)
+		}
+		sb.WriteString("}\n\n")
+
+	case "UNION":
+		memberNames := make([]string, 0, len(introspectedType.PossibleTypes))
+		for _, possibleType := range introspectedType.PossibleTypes {
+			memberNames = append(memberNames, possibleType.Name)
+		}
+		fmt.Fprintf(sb, "union %s = %s\n\n", introspectedType.Name, strings.Join(memberNames, " | "))
+	}
+}
+
+func argsSDL(args []introspectionInputValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		parts = append(parts, fmt.Sprintf("%s: %s", arg.Name, typeRefToSDL(arg.Type)))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+func typeRefToSDL(typeRef introspectionTypeRefFull) string {
+	switch typeRef.Kind {
+	case "NON_NULL":
+		return typeRefToSDL(*typeRef.OfType) + "!"
+	case "LIST":
+		return "[" + typeRefToSDL(*typeRef.OfType) + "]"
+	default:
+		return typeRef.Name
+	}
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testIntrospectionJSONWithPickDirective is a minimal introspection dump
+// whose __schema.directives includes @pick, the custom directive that
+// flattenedVariablesFor/pickedFieldsForVariableDefinition rely on.
+const testIntrospectionJSONWithPickDirective = `{
+  "data": {
+    "__schema": {
+      "queryType": {"name": "Query"},
+      "mutationType": null,
+      "subscriptionType": null,
+      "types": [
+        {
+          "kind": "OBJECT",
+          "name": "Query",
+          "fields": [
+            {"name": "ping", "args": [], "type": {"kind": "SCALAR", "name": "String", "ofType": null}}
+          ]
+        }
+      ],
+      "directives": [
+        {
+          "name": "pick",
+          "locations": ["VARIABLE_DEFINITION"],
+          "args": [
+            {"name": "fields", "type": {"kind": "NON_NULL", "name": "", "ofType": {"kind": "LIST", "name": "", "ofType": {"kind": "NON_NULL", "name": "", "ofType": {"kind": "SCALAR", "name": "String", "ofType": null}}}}}
+          ]
+        }
+      ]
+    }
+  }
+}`
+
+func TestLoadSchemaFromIntrospection_PicksUpCustomDirective(t *testing.T) {
+	var result introspectionResult
+	if err := json.Unmarshal([]byte(testIntrospectionJSONWithPickDirective), &result); err != nil {
+		t.Fatalf("parsing test introspection JSON: %v", err)
+	}
+
+	sdl := introspectionToSDL(result.Data.Schema)
+	if !strings.Contains(sdl, "directive @pick") {
+		t.Fatalf("expected reconstructed SDL to declare @pick, got:\n%s", sdl)
+	}
+
+	schemaFile := filepath.Join(t.TempDir(), "introspection.json")
+	if err := os.WriteFile(schemaFile, []byte(testIntrospectionJSONWithPickDirective), 0644); err != nil {
+		t.Fatalf("writing test introspection dump: %v", err)
+	}
+
+	schema, err := loadSchemaFromIntrospection(schemaFile)
+	if err != nil {
+		t.Fatalf("loadSchemaFromIntrospection: %v", err)
+	}
+
+	directive, ok := schema.Directives["pick"]
+	if !ok {
+		t.Fatalf("expected schema.Directives to contain %q after round-tripping through introspection JSON, got: %v", "pick", schema.Directives)
+	}
+	if len(directive.Locations) != 1 || string(directive.Locations[0]) != "VARIABLE_DEFINITION" {
+		t.Errorf("expected @pick to be declared on VARIABLE_DEFINITION, got locations: %v", directive.Locations)
+	}
+}
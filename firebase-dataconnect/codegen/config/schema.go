@@ -0,0 +1,42 @@
+// Package config loads the GraphQL schema that drives code generation and
+// validates operations against it before any template is executed.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// LoadSchema loads a GraphQL schema from schemaFile, which may be either an
+// SDL file (.graphql, .gql) or an introspection JSON dump (.json) such as
+// the one produced by a standard introspection query. The format is
+// inferred from the file extension.
+func LoadSchema(schemaFile string) (*ast.Schema, error) {
+	switch ext := strings.ToLower(path.Ext(schemaFile)); ext {
+	case ".graphql", ".gql":
+		return loadSchemaFromSDL(schemaFile)
+	case ".json":
+		return loadSchemaFromIntrospection(schemaFile)
+	default:
+		return nil, fmt.Errorf("schema file %q has unrecognized extension %q (expected .graphql, .gql, or .json)", schemaFile, ext)
+	}
+}
+
+func loadSchemaFromSDL(schemaFile string) (*ast.Schema, error) {
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading schema %q: %w", schemaFile, err)
+	}
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: schemaFile, Input: string(data)})
+	if err != nil {
+		return nil, fmt.Errorf("parsing schema %q: %w", schemaFile, err)
+	}
+
+	return schema, nil
+}
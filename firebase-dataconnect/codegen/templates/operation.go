@@ -7,8 +7,6 @@ import (
 	"fmt"
 	"github.com/vektah/gqlparser/v2/ast"
 	"log"
-	"os"
-	"path"
 	"text/template"
 )
 
@@ -19,23 +17,18 @@ func LoadOperationTemplate() (*template.Template, error) {
 	templateName := "operation.gotmpl"
 	log.Println("Loading Go template:", templateName)
 
-	funcMap := template.FuncMap{
-		"fail":                      fail,
-		"kotlinTypeFromGraphQLType": kotlinTypeFromGraphQLType,
-		"isScalarType":              isScalarType,
-		"flattenedVariablesFor":     flattenedVariablesFor,
-		"createConvenienceFunctionVariablesArgumentsRecursiveArgFromConfig":     createConvenienceFunctionVariablesArgumentsRecursiveArgFromConfig,
-		"createConvenienceFunctionVariablesArgumentsRecursiveArgFromArgAndType": createConvenienceFunctionVariablesArgumentsRecursiveArgFromArgAndType,
-		"pickedFieldsForVariableDefinition":                                     pickedFieldsForVariableDefinition,
-	}
-
-	return template.New(templateName).Funcs(funcMap).Parse(operationTemplate)
+	return template.New(templateName).Parse(operationTemplate)
 }
 
 type RenderOperationTemplateConfig struct {
 	KotlinPackage string
 	Operation     *ast.OperationDefinition
 	Schema        *ast.Schema
+
+	// Scalars holds the user-configured custom scalar -> Kotlin type
+	// bindings (see LoadScalarConfig). May be nil, in which case only the
+	// built-in GraphQL scalars are recognized.
+	Scalars *ScalarConfig
 }
 
 func RenderOperationTemplate(
@@ -45,30 +38,48 @@ func RenderOperationTemplate(
 
 	log.Println("Generating:", outputFile)
 
-	var outputBuffer bytes.Buffer
-	err := tmpl.Execute(&outputBuffer, config)
+	content, err := renderOperationTemplateBytes(tmpl, config)
 	if err != nil {
 		return err
 	}
 
-	outputDir := path.Dir(outputFile)
-	_, err = os.Stat(outputDir)
-	if os.IsNotExist(err) {
-		err = os.MkdirAll(outputDir, 0755)
-		if err != nil {
-			return err
-		}
+	return writeGeneratedFile(GeneratedFile{Path: outputFile, Content: content})
+}
+
+// renderOperationTemplateBytes executes tmpl against config and returns the
+// rendered Kotlin source, without writing anything to disk. This is shared
+// by RenderOperationTemplate and operationTemplatePlugin so both funnel
+// their output through the same writer (see writeGeneratedFile).
+func renderOperationTemplateBytes(tmpl *template.Template, config RenderOperationTemplateConfig) ([]byte, error) {
+	funcMap := template.FuncMap{
+		"fail": fail,
+		"kotlinTypeFromGraphQLType": func(node *ast.Type) string {
+			return kotlinTypeFromGraphQLType(node, config.Scalars)
+		},
+		"isScalarType": func(node *ast.Type) bool {
+			return isScalarType(node, config.Scalars)
+		},
+		"flattenedVariablesFor": func(operation *ast.OperationDefinition, schema *ast.Schema) []*ast.VariableDefinition {
+			return flattenedVariablesFor(operation, schema, config.Scalars)
+		},
+		"requiredScalarImports": func(operation *ast.OperationDefinition, schema *ast.Schema) []string {
+			return requiredScalarImports(operation, schema, config.Scalars)
+		},
+		"createConvenienceFunctionVariablesArgumentsRecursiveArgFromConfig":     createConvenienceFunctionVariablesArgumentsRecursiveArgFromConfig,
+		"createConvenienceFunctionVariablesArgumentsRecursiveArgFromArgAndType": createConvenienceFunctionVariablesArgumentsRecursiveArgFromArgAndType,
+		"pickedFieldsForVariableDefinition":                                     pickedFieldsForVariableDefinition,
 	}
+	tmpl = tmpl.Funcs(funcMap)
 
-	err = os.WriteFile(outputFile, outputBuffer.Bytes(), 0644)
-	if err != nil {
-		return err
+	var outputBuffer bytes.Buffer
+	if err := tmpl.Execute(&outputBuffer, config); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return outputBuffer.Bytes(), nil
 }
 
-func kotlinTypeFromGraphQLType(node *ast.Type) string {
+func kotlinTypeFromGraphQLType(node *ast.Type, scalars *ScalarConfig) string {
 	var suffix string
 	if node.NonNull {
 		suffix = ""
@@ -76,10 +87,18 @@ func kotlinTypeFromGraphQLType(node *ast.Type) string {
 		suffix = "?"
 	}
 
-	return kotlinTypeNameFromGraphQLTypeName(node.NamedType) + suffix
+	if node.Elem != nil {
+		return "List<" + kotlinTypeFromGraphQLType(node.Elem, scalars) + ">" + suffix
+	}
+
+	return kotlinTypeNameFromGraphQLTypeName(node.NamedType, scalars) + suffix
 }
 
-func kotlinTypeNameFromGraphQLTypeName(graphQLTypeName string) string {
+func kotlinTypeNameFromGraphQLTypeName(graphQLTypeName string, scalars *ScalarConfig) string {
+	if binding, found := scalars.binding(graphQLTypeName); found {
+		return binding.KotlinType
+	}
+
 	if graphQLTypeName == "Int" {
 		return "Int"
 	} else if graphQLTypeName == "Float" {
@@ -95,8 +114,20 @@ func kotlinTypeNameFromGraphQLTypeName(graphQLTypeName string) string {
 	}
 }
 
-func isScalarType(node *ast.Type) bool {
-	return isScalarTypeName(node.NamedType)
+func isScalarType(node *ast.Type, scalars *ScalarConfig) bool {
+	if node.Elem != nil {
+		return false
+	}
+	return isScalarTypeName(node.NamedType) || isCustomScalarTypeName(node.NamedType, scalars)
+}
+
+// innermostElementType unwraps any list wrappers (e.g. the two layers of
+// Elem in `[[User!]]!`) down to the underlying named type.
+func innermostElementType(typeNode *ast.Type) *ast.Type {
+	for typeNode.Elem != nil {
+		typeNode = typeNode.Elem
+	}
+	return typeNode
 }
 
 func isScalarTypeName(typeName string) bool {
@@ -115,16 +146,22 @@ func isScalarTypeName(typeName string) bool {
 	}
 }
 
-func flattenedVariablesFor(operation *ast.OperationDefinition, schema *ast.Schema) []*ast.VariableDefinition {
+func isCustomScalarTypeName(typeName string, scalars *ScalarConfig) bool {
+	_, found := scalars.binding(typeName)
+	return found
+}
+
+func flattenedVariablesFor(operation *ast.OperationDefinition, schema *ast.Schema, scalars *ScalarConfig) []*ast.VariableDefinition {
 	flattenedVariables := make([]*ast.VariableDefinition, 0, 0)
 
 	for _, variableDefinition := range operation.VariableDefinitions {
-		if isScalarType(variableDefinition.Type) {
+		elementType := innermostElementType(variableDefinition.Type)
+		if isScalarType(elementType, scalars) {
 			flattenedVariables = append(flattenedVariables, variableDefinition)
 			continue
 		}
 
-		childFlattenedVariables := flattenedVariablesForType(variableDefinition.Type, schema)
+		childFlattenedVariables := flattenedVariablesForType(elementType, schema, scalars)
 		pickedFieldDefinitions := pickedFieldsForVariableDefinition(variableDefinition)
 		pickedFieldNames := fieldDefinitionByFieldNameMapFromFieldDefinitions(pickedFieldDefinitions)
 		for _, childFlattenedVariable := range childFlattenedVariables {
@@ -138,18 +175,19 @@ func flattenedVariablesFor(operation *ast.OperationDefinition, schema *ast.Schem
 	return flattenedVariables
 }
 
-func flattenedVariablesForType(typeNode *ast.Type, schema *ast.Schema) []*ast.VariableDefinition {
+func flattenedVariablesForType(typeNode *ast.Type, schema *ast.Schema, scalars *ScalarConfig) []*ast.VariableDefinition {
 	flattenedVariables := make([]*ast.VariableDefinition, 0, 0)
 
 	typeInfo := schema.Types[typeNode.NamedType]
 	for _, field := range typeInfo.Fields {
-		if isScalarType(field.Type) {
+		fieldElementType := innermostElementType(field.Type)
+		if isScalarType(fieldElementType, scalars) {
 			flattenedVariables = append(flattenedVariables, &ast.VariableDefinition{
 				Variable: field.Name,
 				Type:     field.Type,
 			})
 		} else {
-			flattenedVariables = append(flattenedVariables, flattenedVariablesForType(field.Type, schema)...)
+			flattenedVariables = append(flattenedVariables, flattenedVariablesForType(fieldElementType, schema, scalars)...)
 		}
 	}
 
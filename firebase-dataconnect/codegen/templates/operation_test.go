@@ -0,0 +1,155 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const testSchemaSDL = `
+directive @pick(fields: [String!]!) on VARIABLE_DEFINITION
+
+input Address {
+  city: String!
+  zip: String!
+}
+
+input ItemInput {
+  due: String
+  addresses: [Address!]!
+}
+
+type Query {
+  dummy: String
+}
+`
+
+func mustLoadTestSchema(t *testing.T) *ast.Schema {
+	t.Helper()
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "test.graphql", Input: testSchemaSDL})
+	if err != nil {
+		t.Fatalf("loading test schema: %v", err)
+	}
+	return schema
+}
+
+func pickDirective(fieldNames ...string) *ast.Directive {
+	children := make(ast.ChildValueList, 0, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		children = append(children, &ast.ChildValue{Value: &ast.Value{Raw: fieldName, Kind: ast.StringValue}})
+	}
+	return &ast.Directive{
+		Name: "pick",
+		Arguments: ast.ArgumentList{
+			{Name: "fields", Value: &ast.Value{Kind: ast.ListValue, Children: children}},
+		},
+	}
+}
+
+func TestKotlinTypeFromGraphQLType_Lists(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeNode *ast.Type
+		want     string
+	}{
+		{
+			name:     "scalar",
+			typeNode: &ast.Type{NamedType: "String", NonNull: true},
+			want:     "String",
+		},
+		{
+			name:     "non-null list of non-null scalar",
+			typeNode: &ast.Type{Elem: &ast.Type{NamedType: "Int", NonNull: true}, NonNull: true},
+			want:     "List<Int>",
+		},
+		{
+			name:     "nullable list of nullable scalar",
+			typeNode: &ast.Type{Elem: &ast.Type{NamedType: "Int"}},
+			want:     "List<Int?>?",
+		},
+		{
+			name: "list of list of object",
+			typeNode: &ast.Type{
+				Elem: &ast.Type{
+					Elem:    &ast.Type{NamedType: "ItemInput", NonNull: true},
+					NonNull: true,
+				},
+				NonNull: true,
+			},
+			want: "List<List<ItemInput>>",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := kotlinTypeFromGraphQLType(tc.typeNode, nil)
+			if got != tc.want {
+				t.Errorf("kotlinTypeFromGraphQLType(%+v) = %q, want %q", tc.typeNode, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsScalarType_ListsAreNeverScalar(t *testing.T) {
+	listOfScalar := &ast.Type{Elem: &ast.Type{NamedType: "Int", NonNull: true}, NonNull: true}
+	if isScalarType(listOfScalar, nil) {
+		t.Errorf("isScalarType(%+v) = true, want false: a list is never itself a scalar", listOfScalar)
+	}
+}
+
+func TestFlattenedVariablesForType_ListOfScalarIsLeafListOfObjectRecurses(t *testing.T) {
+	schema := mustLoadTestSchema(t)
+
+	variables := flattenedVariablesForType(&ast.Type{NamedType: "ItemInput"}, schema, nil)
+
+	var sawDue, sawAddresses, sawCity bool
+	for _, v := range variables {
+		switch v.Variable {
+		case "due":
+			sawDue = true
+		case "addresses":
+			sawAddresses = true
+		case "city":
+			sawCity = true
+		}
+	}
+
+	if !sawDue {
+		t.Error(`expected scalar field "due" to be flattened as a leaf variable`)
+	}
+	if sawAddresses {
+		t.Error(`expected "addresses" (a list of objects) to be recursed into, not flattened as a leaf`)
+	}
+	if !sawCity {
+		t.Error(`expected "city", a field of the list-of-object "addresses", to be reached by recursion`)
+	}
+}
+
+func TestFlattenedVariablesFor_NestedListOfListOfInputObjectWithPick(t *testing.T) {
+	schema := mustLoadTestSchema(t)
+
+	variableDefinition := &ast.VariableDefinition{
+		Variable: "items",
+		Type: &ast.Type{
+			Elem: &ast.Type{
+				Elem:    &ast.Type{NamedType: "ItemInput", NonNull: true},
+				NonNull: true,
+			},
+			NonNull: true,
+		},
+		Definition: schema.Types["ItemInput"],
+		Directives: ast.DirectiveList{pickDirective("due")},
+	}
+	operation := &ast.OperationDefinition{
+		Name:                "Test",
+		VariableDefinitions: ast.VariableDefinitionList{variableDefinition},
+	}
+
+	flattened := flattenedVariablesFor(operation, schema, nil)
+
+	if len(flattened) != 1 || flattened[0].Variable != "due" {
+		t.Fatalf("flattenedVariablesFor([[ItemInput!]!]! @pick(fields: [\"due\"])) = %+v, want only [\"due\"]", flattened)
+	}
+}
@@ -0,0 +1,164 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"text/template"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// GeneratedFile is a single file emitted by a Plugin's GenerateArtifact.
+// Plugins return the file's content rather than writing it themselves, so
+// that GenerateArtifacts can detect path collisions across plugins before
+// anything is written to disk.
+type GeneratedFile struct {
+	Path    string
+	Content []byte
+}
+
+// Plugin is the interface every code generation plugin implements. A
+// plugin additionally implements SchemaMutator, OperationMutator, and/or
+// ArtifactGenerator depending on which hooks it needs.
+type Plugin interface {
+	Name() string
+}
+
+type SchemaMutator interface {
+	MutateSchema(schema *ast.Schema) error
+}
+
+type OperationMutator interface {
+	MutateOperation(operation *ast.OperationDefinition) error
+}
+
+// ArtifactGenerator is implemented by plugins that emit generated files
+// (e.g. a test double, a JSON serializer, a Compose UI stub) for a given
+// operation, alongside the primary Kotlin output.
+type ArtifactGenerator interface {
+	GenerateArtifact(config RenderOperationTemplateConfig) ([]GeneratedFile, error)
+}
+
+// Registry holds the ordered set of plugins that participate in code
+// generation. Plugins run in registration order.
+type Registry struct {
+	plugins []Plugin
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register appends plugin to the registry. It is an error to register two
+// plugins with the same Name.
+func (r *Registry) Register(plugin Plugin) error {
+	for _, existing := range r.plugins {
+		if existing.Name() == plugin.Name() {
+			return fmt.Errorf("plugin %q is already registered", plugin.Name())
+		}
+	}
+	r.plugins = append(r.plugins, plugin)
+	return nil
+}
+
+func (r *Registry) MutateSchema(schema *ast.Schema) error {
+	for _, plugin := range r.plugins {
+		mutator, ok := plugin.(SchemaMutator)
+		if !ok {
+			continue
+		}
+		if err := mutator.MutateSchema(schema); err != nil {
+			return fmt.Errorf("plugin %q: MutateSchema: %w", plugin.Name(), err)
+		}
+	}
+	return nil
+}
+
+func (r *Registry) MutateOperation(operation *ast.OperationDefinition) error {
+	for _, plugin := range r.plugins {
+		mutator, ok := plugin.(OperationMutator)
+		if !ok {
+			continue
+		}
+		if err := mutator.MutateOperation(operation); err != nil {
+			return fmt.Errorf("plugin %q: MutateOperation: %w", plugin.Name(), err)
+		}
+	}
+	return nil
+}
+
+// GenerateArtifacts writes the combined set of files generated by every
+// registered ArtifactGenerator plugin (including the built-in operation and
+// resolver plugins) and returns them. It is an error for two plugins to
+// generate a file at the same Path; that check runs against every plugin's
+// output before anything is written, so a colliding plugin never clobbers
+// another's file.
+func (r *Registry) GenerateArtifacts(config RenderOperationTemplateConfig) ([]GeneratedFile, error) {
+	pluginNameByPath := make(map[string]string)
+	var artifacts []GeneratedFile
+
+	for _, plugin := range r.plugins {
+		generator, ok := plugin.(ArtifactGenerator)
+		if !ok {
+			continue
+		}
+
+		files, err := generator.GenerateArtifact(config)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %q: GenerateArtifact: %w", plugin.Name(), err)
+		}
+
+		for _, file := range files {
+			if owner, claimed := pluginNameByPath[file.Path]; claimed {
+				return nil, fmt.Errorf("plugin %q and plugin %q both generated %q", owner, plugin.Name(), file.Path)
+			}
+			pluginNameByPath[file.Path] = plugin.Name()
+			artifacts = append(artifacts, file)
+		}
+	}
+
+	for _, file := range artifacts {
+		if err := writeGeneratedFile(file); err != nil {
+			return nil, err
+		}
+	}
+
+	return artifacts, nil
+}
+
+// writeGeneratedFile writes file.Content to file.Path, creating any missing
+// parent directories.
+func writeGeneratedFile(file GeneratedFile) error {
+	outputDir := path.Dir(file.Path)
+	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(file.Path, file.Content, 0644)
+}
+
+// operationTemplatePlugin wraps RenderOperationTemplate as a Plugin.
+type operationTemplatePlugin struct {
+	tmpl       *template.Template
+	outputFile string
+}
+
+func NewOperationTemplatePlugin(tmpl *template.Template, outputFile string) Plugin {
+	return &operationTemplatePlugin{tmpl: tmpl, outputFile: outputFile}
+}
+
+func (p *operationTemplatePlugin) Name() string {
+	return "operation"
+}
+
+func (p *operationTemplatePlugin) GenerateArtifact(config RenderOperationTemplateConfig) ([]GeneratedFile, error) {
+	content, err := renderOperationTemplateBytes(p.tmpl, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return []GeneratedFile{{Path: p.outputFile, Content: content}}, nil
+}
@@ -0,0 +1,81 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+type fakeArtifactPlugin struct {
+	name  string
+	files []GeneratedFile
+}
+
+func (p *fakeArtifactPlugin) Name() string { return p.name }
+
+func (p *fakeArtifactPlugin) GenerateArtifact(config RenderOperationTemplateConfig) ([]GeneratedFile, error) {
+	return p.files, nil
+}
+
+func testOperationConfig() RenderOperationTemplateConfig {
+	return RenderOperationTemplateConfig{
+		Operation: &ast.OperationDefinition{Name: "Test"},
+		Schema:    &ast.Schema{Types: map[string]*ast.Definition{}},
+	}
+}
+
+func TestGenerateArtifacts_DetectsCollisionWithBuiltinOperationPlugin(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputFile := filepath.Join(tmpDir, "Operation.kt")
+
+	tmpl := template.Must(template.New("operation").Parse("package test\n"))
+	registry := NewRegistry()
+	if err := registry.Register(NewOperationTemplatePlugin(tmpl, outputFile)); err != nil {
+		t.Fatalf("registering operation plugin: %v", err)
+	}
+	if err := registry.Register(&fakeArtifactPlugin{
+		name:  "colliding",
+		files: []GeneratedFile{{Path: outputFile, Content: []byte("collision")}},
+	}); err != nil {
+		t.Fatalf("registering fake plugin: %v", err)
+	}
+
+	if _, err := registry.GenerateArtifacts(testOperationConfig()); err == nil {
+		t.Fatal("expected GenerateArtifacts to fail when a plugin's output path collides with the built-in operation plugin's, got nil error")
+	}
+
+	if _, err := os.Stat(outputFile); !os.IsNotExist(err) {
+		t.Errorf("expected %q to not be written after a collision is detected, stat returned: %v", outputFile, err)
+	}
+}
+
+func TestGenerateArtifacts_WritesEveryNonCollidingFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	operationFile := filepath.Join(tmpDir, "Operation.kt")
+	extraFile := filepath.Join(tmpDir, "Extra.kt")
+
+	tmpl := template.Must(template.New("operation").Parse("package test\n"))
+	registry := NewRegistry()
+	if err := registry.Register(NewOperationTemplatePlugin(tmpl, operationFile)); err != nil {
+		t.Fatalf("registering operation plugin: %v", err)
+	}
+	if err := registry.Register(&fakeArtifactPlugin{
+		name:  "extra",
+		files: []GeneratedFile{{Path: extraFile, Content: []byte("extra")}},
+	}); err != nil {
+		t.Fatalf("registering fake plugin: %v", err)
+	}
+
+	if _, err := registry.GenerateArtifacts(testOperationConfig()); err != nil {
+		t.Fatalf("GenerateArtifacts: %v", err)
+	}
+
+	for _, file := range []string{operationFile, extraFile} {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("expected %q to have been written: %v", file, err)
+		}
+	}
+}
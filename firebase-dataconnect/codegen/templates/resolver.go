@@ -0,0 +1,135 @@
+package templates
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+//go:embed resolver.gotmpl
+var resolverTemplate string
+
+// userImplementationMarker delimits the hand-written implementation half of
+// a generated resolver stub. Everything above the marker is regenerated on
+// every run; everything at or after it is copied forward untouched.
+const userImplementationMarker = "// BEGIN USER IMPLEMENTATION -- preserved across regeneration, edit freely below this line"
+
+func LoadResolverTemplate() (*template.Template, error) {
+	templateName := "resolver.gotmpl"
+	log.Println("Loading Go template:", templateName)
+
+	return template.New(templateName).Parse(resolverTemplate)
+}
+
+// RenderResolverTemplateConfig configures RenderResolverTemplate.
+type RenderResolverTemplateConfig struct {
+	KotlinPackage string
+	Operation     *ast.OperationDefinition
+	Schema        *ast.Schema
+}
+
+// RenderResolverTemplate emits a Kotlin interface + default stub for
+// config.Operation that the app can implement to intercept the
+// request/response, e.g. for logging, offline caching, or mocking in
+// tests. Re-running only regenerates the interface half of outputFile:
+// anything the user wrote at or after userImplementationMarker in a prior
+// run is preserved verbatim.
+func RenderResolverTemplate(
+	tmpl *template.Template,
+	outputFile string,
+	config RenderResolverTemplateConfig) error {
+
+	log.Println("Generating:", outputFile)
+
+	content, err := renderResolverTemplateBytes(tmpl, outputFile, config)
+	if err != nil {
+		return err
+	}
+
+	return writeGeneratedFile(GeneratedFile{Path: outputFile, Content: content})
+}
+
+// renderResolverTemplateBytes executes tmpl against config and returns the
+// rendered Kotlin source, with any preserved user implementation from
+// outputFile's current contents appended, without writing anything to disk.
+// This is shared by RenderResolverTemplate and resolverTemplatePlugin so
+// both funnel their output through the same writer (see writeGeneratedFile).
+func renderResolverTemplateBytes(tmpl *template.Template, outputFile string, config RenderResolverTemplateConfig) ([]byte, error) {
+	funcMap := template.FuncMap{
+		"kotlinTypeFromGraphQLType": func(node *ast.Type) string {
+			return kotlinTypeFromGraphQLType(node, nil)
+		},
+		"userImplementationMarker": func() string { return userImplementationMarker },
+	}
+	tmpl = tmpl.Funcs(funcMap)
+
+	var outputBuffer bytes.Buffer
+	if err := tmpl.Execute(&outputBuffer, config); err != nil {
+		return nil, err
+	}
+
+	generated := outputBuffer.String()
+	if preserved, err := preservedUserImplementation(outputFile); err == nil {
+		if markerIndex := strings.Index(generated, userImplementationMarker); markerIndex != -1 {
+			generated = generated[:markerIndex]
+		}
+		generated += preserved
+	}
+
+	return []byte(generated), nil
+}
+
+// preservedUserImplementation returns everything at or after
+// userImplementationMarker in outputFile's current contents, so a
+// regeneration run can append it back unchanged. It returns an error if
+// outputFile doesn't exist yet or doesn't contain the marker, either of
+// which means there is nothing to preserve.
+func preservedUserImplementation(outputFile string) (string, error) {
+	existing, err := os.ReadFile(outputFile)
+	if err != nil {
+		return "", err
+	}
+
+	markerIndex := strings.Index(string(existing), userImplementationMarker)
+	if markerIndex == -1 {
+		return "", fmt.Errorf("no user implementation marker found in %q", outputFile)
+	}
+
+	return string(existing[markerIndex:]), nil
+}
+
+// resolverTemplatePlugin wraps RenderResolverTemplate as a Plugin. Projects
+// that don't want resolver stubs simply don't register it.
+type resolverTemplatePlugin struct {
+	tmpl       *template.Template
+	outputFile string
+}
+
+func NewResolverTemplatePlugin(tmpl *template.Template, outputFile string) Plugin {
+	return &resolverTemplatePlugin{tmpl: tmpl, outputFile: outputFile}
+}
+
+func (p *resolverTemplatePlugin) Name() string {
+	return "resolver"
+}
+
+func (p *resolverTemplatePlugin) GenerateArtifact(config RenderOperationTemplateConfig) ([]GeneratedFile, error) {
+	resolverConfig := RenderResolverTemplateConfig{
+		KotlinPackage: config.KotlinPackage,
+		Operation:     config.Operation,
+		Schema:        config.Schema,
+	}
+
+	content, err := renderResolverTemplateBytes(p.tmpl, p.outputFile, resolverConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return []GeneratedFile{{Path: p.outputFile, Content: content}}, nil
+}
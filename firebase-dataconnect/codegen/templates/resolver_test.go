@@ -0,0 +1,55 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const testResolverTemplateSource = `interface TestResolver {
+    fun onRequest()
+}
+
+{{userImplementationMarker}}
+class DefaultTestResolver : TestResolver {
+    override fun onRequest() {}
+}
+`
+
+func TestRenderResolverTemplate_RegeneratingDoesNotDuplicateMarker(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputFile := filepath.Join(tmpDir, "TestResolver.kt")
+
+	tmpl := template.Must(template.New("resolver").Parse(testResolverTemplateSource))
+	config := RenderResolverTemplateConfig{
+		Operation: &ast.OperationDefinition{Name: "Test"},
+		Schema:    &ast.Schema{Types: map[string]*ast.Definition{}},
+	}
+
+	if err := RenderResolverTemplate(tmpl, outputFile, config); err != nil {
+		t.Fatalf("first RenderResolverTemplate run: %v", err)
+	}
+	firstRun, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("reading output after first run: %v", err)
+	}
+
+	if err := RenderResolverTemplate(tmpl, outputFile, config); err != nil {
+		t.Fatalf("second RenderResolverTemplate run: %v", err)
+	}
+	secondRun, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("reading output after second run: %v", err)
+	}
+
+	if markerCount := strings.Count(string(secondRun), userImplementationMarker); markerCount != 1 {
+		t.Errorf("expected exactly one marker after regenerating, got %d in:\n%s", markerCount, secondRun)
+	}
+	if string(secondRun) != string(firstRun) {
+		t.Errorf("expected regenerating an untouched file to be a no-op, got:\nfirst run:\n%s\nsecond run:\n%s", firstRun, secondRun)
+	}
+}
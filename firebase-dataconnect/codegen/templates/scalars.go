@@ -0,0 +1,114 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// ScalarBinding describes how a single custom GraphQL scalar should be
+// rendered in generated Kotlin code.
+type ScalarBinding struct {
+	// KotlinType is the fully-qualified or simple Kotlin type to substitute
+	// for the scalar, e.g. "kotlinx.datetime.LocalDate" or "Long".
+	KotlinType string `json:"kotlinType" yaml:"kotlinType"`
+
+	// Import is the import statement required to reference KotlinType, if
+	// any. Left empty for types already in scope (e.g. "Long").
+	Import string `json:"import,omitempty" yaml:"import,omitempty"`
+}
+
+// ScalarConfig is the user-supplied set of custom scalar -> Kotlin type
+// bindings, threaded through RenderOperationTemplateConfig so the template
+// funcs can consult it.
+type ScalarConfig struct {
+	Scalars map[string]ScalarBinding `json:"scalars" yaml:"scalars"`
+}
+
+// LoadScalarConfig reads a scalar binding config from configFile. The file
+// format is inferred from its extension (.yaml, .yml, or .json).
+func LoadScalarConfig(configFile string) (*ScalarConfig, error) {
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading scalar config %q: %w", configFile, err)
+	}
+
+	config := &ScalarConfig{}
+	switch ext := strings.ToLower(path.Ext(configFile)); ext {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("parsing scalar config %q as YAML: %w", configFile, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("parsing scalar config %q as JSON: %w", configFile, err)
+		}
+	default:
+		return nil, fmt.Errorf("scalar config %q has unrecognized extension %q (expected .yaml, .yml, or .json)", configFile, ext)
+	}
+
+	return config, nil
+}
+
+// binding looks up the custom binding for a GraphQL scalar name, if any. A
+// nil receiver is treated as "no custom bindings configured".
+func (c *ScalarConfig) binding(graphQLTypeName string) (ScalarBinding, bool) {
+	if c == nil {
+		return ScalarBinding{}, false
+	}
+	binding, found := c.Scalars[graphQLTypeName]
+	return binding, found
+}
+
+// requiredScalarImports returns the sorted, de-duplicated list of Kotlin
+// import statements needed to reference every custom-scalar-bound type
+// reachable from operation's variables.
+func requiredScalarImports(operation *ast.OperationDefinition, schema *ast.Schema, scalars *ScalarConfig) []string {
+	imports := make(map[string]struct{})
+
+	var visit func(typeNode *ast.Type)
+	visit = func(typeNode *ast.Type) {
+		if typeNode == nil {
+			return
+		}
+		if typeNode.Elem != nil {
+			visit(typeNode.Elem)
+			return
+		}
+		if binding, found := scalars.binding(typeNode.NamedType); found {
+			if binding.Import != "" {
+				imports[binding.Import] = struct{}{}
+			}
+			return
+		}
+		if isScalarTypeName(typeNode.NamedType) {
+			return
+		}
+
+		typeInfo := schema.Types[typeNode.NamedType]
+		if typeInfo == nil {
+			return
+		}
+		for _, field := range typeInfo.Fields {
+			visit(field.Type)
+		}
+	}
+
+	for _, variableDefinition := range operation.VariableDefinitions {
+		visit(variableDefinition.Type)
+	}
+
+	sortedImports := make([]string, 0, len(imports))
+	for imp := range imports {
+		sortedImports = append(sortedImports, imp)
+	}
+	sort.Strings(sortedImports)
+
+	return sortedImports
+}